@@ -0,0 +1,79 @@
+package sqlhelper
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Queryer is implemented by *sql.DB, *sql.Tx and *sql.Conn. It lets QueryScanAll and QueryScanOne accept
+// any of them interchangeably.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// stmtQueryer adapts a *sql.Stmt, whose QueryContext takes no query string, to the Queryer interface, so
+// the Stmt-specific functions throughout this package can be expressed as thin wrappers around their
+// Queryer-based counterparts. The query string passed through QueryContext is ignored.
+type stmtQueryer struct {
+	stmt *sql.Stmt
+}
+
+func (s stmtQueryer) QueryContext(ctx context.Context, _ string, args ...interface{}) (*sql.Rows, error) {
+	return s.stmt.QueryContext(ctx, args...)
+}
+
+// StmtScanAllContext is the context-aware variant of StmtScanAll.
+func StmtScanAllContext(ctx context.Context, stmt *sql.Stmt, dst MultiScannable, args ...interface{}) error {
+	return QueryScanAll(ctx, stmtQueryer{stmt}, dst, "", args...)
+}
+
+// StmtScanOne performs the prepared statement stmt with arguments args and stores the single result row
+// in dst. It returns sql.ErrNoRows if the result set is empty, and an error if it has more than one row.
+func StmtScanOne(stmt *sql.Stmt, dst SingleScannable, args ...interface{}) error {
+	return StmtScanOneContext(context.Background(), stmt, dst, args...)
+}
+
+// StmtScanOneContext is the context-aware variant of StmtScanOne.
+func StmtScanOneContext(ctx context.Context, stmt *sql.Stmt, dst SingleScannable, args ...interface{}) error {
+	return QueryScanOne(ctx, stmtQueryer{stmt}, dst, "", args...)
+}
+
+// QueryScanAll runs query with arguments args against q and stores all result rows in dst. Unlike
+// StmtScanAll it works with any Queryer, so it also accepts *sql.Tx and *sql.Conn, and the query does not
+// need to be prepared beforehand.
+func QueryScanAll(ctx context.Context, q Queryer, dst MultiScannable, query string, args ...interface{}) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := scanAllRows(rows, dst); err != nil {
+		return err
+	}
+
+	return rows.Err()
+}
+
+// QueryScanOne runs query with arguments args against q and stores the single result row in dst. It
+// returns sql.ErrNoRows if the result set is empty, and an error if it has more than one row.
+func QueryScanOne(ctx context.Context, q Queryer, dst SingleScannable, query string, args ...interface{}) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return scanOneRow(rows, dst)
+}
+
+// TxQueryScanAll is QueryScanAll specialized for *sql.Tx, letting callers scan query results run as part
+// of a transaction.
+func TxQueryScanAll(ctx context.Context, tx *sql.Tx, dst MultiScannable, query string, args ...interface{}) error {
+	return QueryScanAll(ctx, tx, dst, query, args...)
+}
+
+// TxQueryScanOne is QueryScanOne specialized for *sql.Tx.
+func TxQueryScanOne(ctx context.Context, tx *sql.Tx, dst SingleScannable, query string, args ...interface{}) error {
+	return QueryScanOne(ctx, tx, dst, query, args...)
+}
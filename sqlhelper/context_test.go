@@ -0,0 +1,143 @@
+package sqlhelper
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type ctxLabel struct {
+	ID   int64
+	Name string
+}
+
+func (l *ctxLabel) SqlScanInterface() []interface{} {
+	return []interface{}{&l.ID, &l.Name}
+}
+
+type ctxLabels []*ctxLabel
+
+func (l *ctxLabels) NewElement() SingleScannable {
+	e := &ctxLabel{}
+	*l = append(*l, e)
+	return e
+}
+
+func TestQueryScanAllSuccess(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+			{int64(2), "Bob"},
+		},
+	})
+
+	var labels ctxLabels
+	if err := QueryScanAll(context.Background(), db, &labels, "select id, name from labels"); err != nil {
+		t.Fatalf("QueryScanAll: %v", err)
+	}
+
+	want := ctxLabels{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Bob"}}
+	if !reflect.DeepEqual(labels, want) {
+		t.Fatalf("labels = %+v, want %+v", labels, want)
+	}
+}
+
+func TestQueryScanOneSuccess(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+		},
+	})
+
+	var label ctxLabel
+	if err := QueryScanOne(context.Background(), db, &label, "select id, name from labels"); err != nil {
+		t.Fatalf("QueryScanOne: %v", err)
+	}
+
+	if want := (ctxLabel{ID: 1, Name: "Ann"}); label != want {
+		t.Fatalf("label = %+v, want %+v", label, want)
+	}
+}
+
+func TestQueryScanOneNoRows(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows:    [][]driver.Value{},
+	})
+
+	var label ctxLabel
+	err := QueryScanOne(context.Background(), db, &label, "select id, name from labels")
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("QueryScanOne err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestQueryScanOneTooManyRows(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+			{int64(2), "Bob"},
+		},
+	})
+
+	var label ctxLabel
+	err := QueryScanOne(context.Background(), db, &label, "select id, name from labels")
+	if !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("QueryScanOne err = %v, want ErrTooManyRows", err)
+	}
+}
+
+func TestTxQueryScanAllSuccess(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+		},
+	})
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("db.Begin: %v", err)
+	}
+	defer tx.Rollback()
+
+	var labels ctxLabels
+	if err := TxQueryScanAll(context.Background(), tx, &labels, "select id, name from labels"); err != nil {
+		t.Fatalf("TxQueryScanAll: %v", err)
+	}
+
+	want := ctxLabels{{ID: 1, Name: "Ann"}}
+	if !reflect.DeepEqual(labels, want) {
+		t.Fatalf("labels = %+v, want %+v", labels, want)
+	}
+}
+
+func TestTxQueryScanOneSuccess(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+		},
+	})
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("db.Begin: %v", err)
+	}
+	defer tx.Rollback()
+
+	var label ctxLabel
+	if err := TxQueryScanOne(context.Background(), tx, &label, "select id, name from labels"); err != nil {
+		t.Fatalf("TxQueryScanOne: %v", err)
+	}
+
+	if want := (ctxLabel{ID: 1, Name: "Ann"}); label != want {
+		t.Fatalf("label = %+v, want %+v", label, want)
+	}
+}
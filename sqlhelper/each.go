@@ -0,0 +1,66 @@
+package sqlhelper
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrStopIteration can be returned by the fn passed to StmtScanEach to stop iterating early without that
+// being reported as a failure.
+var ErrStopIteration = errors.New("sqlhelper: stop iteration")
+
+// StmtScanEach performs the prepared statement stmt with arguments args and, for each result row, scans it
+// into a fresh SingleScannable obtained from factory and passes it to fn. Unlike StmtScanAll it never
+// materializes the whole result set in memory, making it suitable for large reporting or ETL queries.
+// Iteration stops as soon as fn returns ErrStopIteration, in which case StmtScanEach itself returns nil,
+// or any other error, which is returned as-is.
+func StmtScanEach(stmt *sql.Stmt, factory func() SingleScannable, fn func(SingleScannable) error, args ...interface{}) error {
+	return StmtScanEachContext(context.Background(), stmt, factory, fn, args...)
+}
+
+// StmtScanEachContext is the context-aware variant of StmtScanEach.
+func StmtScanEachContext(ctx context.Context, stmt *sql.Stmt, factory func() SingleScannable, fn func(SingleScannable) error, args ...interface{}) error {
+	return QueryScanEach(ctx, stmtQueryer{stmt}, factory, fn, "", args...)
+}
+
+// QueryScanEach runs query with arguments args against q and streams the result through factory and fn as
+// StmtScanEach does.
+func QueryScanEach(ctx context.Context, q Queryer, factory func() SingleScannable, fn func(SingleScannable) error, query string, args ...interface{}) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := scanEachRow(rows, factory, fn); err != nil {
+		return err
+	}
+
+	return rows.Err()
+}
+
+// TxQueryScanEach is QueryScanEach specialized for *sql.Tx.
+func TxQueryScanEach(ctx context.Context, tx *sql.Tx, factory func() SingleScannable, fn func(SingleScannable) error, query string, args ...interface{}) error {
+	return QueryScanEach(ctx, tx, factory, fn, query, args...)
+}
+
+// scanEachRow scans each remaining row of rows into a fresh value from factory and passes it to fn,
+// stopping without error on ErrStopIteration. It leaves closing rows and checking rows.Err() to the caller.
+func scanEachRow(rows *sql.Rows, factory func() SingleScannable, fn func(SingleScannable) error) error {
+	for rows.Next() {
+		rowContainer := factory()
+		if err := rows.Scan(rowContainer.SqlScanInterface()...); err != nil {
+			return err
+		}
+
+		if err := fn(rowContainer); err != nil {
+			if err == ErrStopIteration {
+				return nil
+			}
+			return err
+		}
+	}
+
+	return nil
+}
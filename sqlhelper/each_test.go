@@ -0,0 +1,125 @@
+package sqlhelper
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStmtScanEachNormalIteration(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+			{int64(2), "Bob"},
+		},
+	})
+
+	stmt, err := db.Prepare("select id, name from labels")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	var got []ctxLabel
+	err = StmtScanEach(stmt, func() SingleScannable { return &ctxLabel{} }, func(s SingleScannable) error {
+		got = append(got, *s.(*ctxLabel))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StmtScanEach: %v", err)
+	}
+
+	want := []ctxLabel{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Bob"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got = %+v, want %+v", got, want)
+	}
+}
+
+func TestStmtScanEachStopIteration(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+			{int64(2), "Bob"},
+			{int64(3), "Cid"},
+		},
+	})
+
+	stmt, err := db.Prepare("select id, name from labels")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	var got []ctxLabel
+	err = StmtScanEach(stmt, func() SingleScannable { return &ctxLabel{} }, func(s SingleScannable) error {
+		got = append(got, *s.(*ctxLabel))
+		if len(got) == 2 {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StmtScanEach: %v", err)
+	}
+
+	want := []ctxLabel{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Bob"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got = %+v, want %+v (iteration should have stopped after 2 rows)", got, want)
+	}
+}
+
+func TestStmtScanEachFnErrorPropagates(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+			{int64(2), "Bob"},
+		},
+	})
+
+	stmt, err := db.Prepare("select id, name from labels")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	wantErr := errors.New("boom")
+	var calls int
+	err = StmtScanEach(stmt, func() SingleScannable { return &ctxLabel{} }, func(s SingleScannable) error {
+		calls++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("StmtScanEach err = %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Fatalf("fn called %d times, want 1 (iteration should stop on the first non-sentinel error)", calls)
+	}
+}
+
+func TestQueryScanEachNormalIteration(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+		},
+	})
+
+	var got []ctxLabel
+	err := QueryScanEach(context.Background(), db, func() SingleScannable { return &ctxLabel{} }, func(s SingleScannable) error {
+		got = append(got, *s.(*ctxLabel))
+		return nil
+	}, "select id, name from labels")
+	if err != nil {
+		t.Fatalf("QueryScanEach: %v", err)
+	}
+
+	want := []ctxLabel{{ID: 1, Name: "Ann"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got = %+v, want %+v", got, want)
+	}
+}
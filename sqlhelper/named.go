@@ -0,0 +1,228 @@
+package sqlhelper
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// BindType identifies the placeholder style a driver expects for positional arguments.
+type BindType int
+
+const (
+	// BindQuestion uses a single `?` for every placeholder (MySQL, SQLite).
+	BindQuestion BindType = iota
+	// BindDollar uses `$1`..`$N` (Postgres).
+	BindDollar
+	// BindAt uses `@p1`..`@pN` (SQL Server).
+	BindAt
+	// BindColon uses `:1`..`:N` (Oracle).
+	BindColon
+)
+
+// DefaultBind is the BindType used by Named and Rebind; neither takes a BindType of its own. Set it once
+// at program start-up to match the driver in use.
+var DefaultBind = BindQuestion
+
+// Named rewrites query, replacing each `:name` placeholder with the positional placeholder of DefaultBind,
+// and returns the argument list in the matching order. arg must be a map[string]interface{} or a struct
+// (or a pointer to one), whose fields are mapped to names as described for StmtStructScanAll. A `:name`
+// sequence inside a string literal or a `::` PostgreSQL cast is left untouched.
+func Named(query string, arg interface{}) (string, []interface{}, error) {
+	rewritten, names := rewriteNamed(query, DefaultBind)
+
+	args, err := namedArgs(names, arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return rewritten, args, nil
+}
+
+// Rebind translates a query using `?` placeholders to DefaultBind's style.
+func Rebind(query string) string {
+	if DefaultBind == BindQuestion {
+		return query
+	}
+
+	var sb strings.Builder
+	var quote rune
+	n := 0
+
+	for _, c := range query {
+		if quote != 0 {
+			sb.WriteRune(c)
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '"':
+			quote = c
+			sb.WriteRune(c)
+		case '?':
+			n++
+			sb.WriteString(bindPlaceholder(DefaultBind, n))
+		default:
+			sb.WriteRune(c)
+		}
+	}
+
+	return sb.String()
+}
+
+// NamedStmtScanAll prepares query against db after rewriting its `:name` placeholders with Named, runs it
+// with the arguments extracted from arg, and scans all result rows into dst as StmtStructScanAll does.
+func NamedStmtScanAll(db *sql.DB, query string, dst interface{}, arg interface{}) error {
+	rewritten, args, err := Named(query, arg)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare(rewritten)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	return StmtStructScanAll(stmt, dst, args...)
+}
+
+// bindPlaceholder returns the n-th (1-based) positional placeholder for bind.
+func bindPlaceholder(bind BindType, n int) string {
+	switch bind {
+	case BindDollar:
+		return fmt.Sprintf("$%d", n)
+	case BindAt:
+		return fmt.Sprintf("@p%d", n)
+	case BindColon:
+		return fmt.Sprintf(":%d", n)
+	default:
+		return "?"
+	}
+}
+
+// rewriteNamed replaces every `:name` placeholder in query with bind's positional placeholder, returning
+// the rewritten query and the names in the order they were encountered. String literals and `::` casts
+// are copied through untouched.
+func rewriteNamed(query string, bind BindType) (string, []string) {
+	runes := []rune(query)
+
+	var sb strings.Builder
+	var names []string
+	var quote rune
+	n := 0
+
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		if quote != 0 {
+			sb.WriteRune(c)
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		if c == '\'' || c == '"' {
+			quote = c
+			sb.WriteRune(c)
+			continue
+		}
+
+		if c == ':' {
+			if i+1 < len(runes) && runes[i+1] == ':' {
+				sb.WriteString("::")
+				i++
+				continue
+			}
+
+			if i+1 < len(runes) && isNameStartRune(runes[i+1]) {
+				j := i + 1
+				for j < len(runes) && isNameRune(runes[j]) {
+					j++
+				}
+
+				names = append(names, string(runes[i+1:j]))
+				n++
+				sb.WriteString(bindPlaceholder(bind, n))
+				i = j - 1
+				continue
+			}
+		}
+
+		sb.WriteRune(c)
+	}
+
+	return sb.String(), names
+}
+
+func isNameStartRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// namedArgs resolves each of names against arg, which must be a map[string]interface{} or a struct (or a
+// pointer to one).
+func namedArgs(names []string, arg interface{}) ([]interface{}, error) {
+	if m, ok := arg.(map[string]interface{}); ok {
+		args := make([]interface{}, len(names))
+		for i, name := range names {
+			val, ok := m[name]
+			if !ok {
+				return nil, fmt.Errorf("sqlhelper: no argument for named parameter %q", name)
+			}
+			args[i] = val
+		}
+		return args, nil
+	}
+
+	rv := reflect.ValueOf(arg)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sqlhelper: arg must be a map[string]interface{} or a struct, got %T", arg)
+	}
+
+	fields := fieldsOf(rv.Type())
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		index, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("sqlhelper: no field for named parameter %q", name)
+		}
+
+		field, ok := fieldByIndexForRead(rv, index)
+		if !ok {
+			return nil, fmt.Errorf("sqlhelper: nil embedded field while resolving named parameter %q", name)
+		}
+		args[i] = field.Interface()
+	}
+
+	return args, nil
+}
+
+// fieldByIndexForRead returns v's field at index, reporting ok=false instead of panicking if index walks
+// through a nil anonymous pointer-to-struct field.
+func fieldByIndexForRead(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return reflect.Value{}, false
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
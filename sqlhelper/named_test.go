@@ -0,0 +1,98 @@
+package sqlhelper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func withBind(t *testing.T, bind BindType) {
+	t.Helper()
+	old := DefaultBind
+	DefaultBind = bind
+	t.Cleanup(func() { DefaultBind = old })
+}
+
+func TestRewriteNamedSkipsQuotedLiteral(t *testing.T) {
+	withBind(t, BindDollar)
+
+	query, names := rewriteNamed("SELECT * FROM t WHERE name = ':not_a_param' AND id = :id", DefaultBind)
+
+	wantQuery := "SELECT * FROM t WHERE name = ':not_a_param' AND id = $1"
+	if query != wantQuery {
+		t.Fatalf("query = %q, want %q", query, wantQuery)
+	}
+	if want := []string{"id"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+}
+
+func TestRewriteNamedSkipsPostgresCast(t *testing.T) {
+	withBind(t, BindDollar)
+
+	query, names := rewriteNamed("SELECT :id::int", DefaultBind)
+
+	if want := "SELECT $1::int"; query != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+	if want := []string{"id"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+}
+
+func TestRewriteNamedRepeatedParam(t *testing.T) {
+	withBind(t, BindQuestion)
+
+	query, names := rewriteNamed("SELECT :id, :id", BindQuestion)
+
+	if want := "SELECT ?, ?"; query != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+	if want := []string{"id", "id"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+}
+
+func TestNamedWithMapArg(t *testing.T) {
+	withBind(t, BindQuestion)
+
+	query, args, err := Named("SELECT * FROM t WHERE id = :id OR parent_id = :id", map[string]interface{}{"id": 5})
+	if err != nil {
+		t.Fatalf("Named: %v", err)
+	}
+
+	if want := "SELECT * FROM t WHERE id = ? OR parent_id = ?"; query != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+	if want := []interface{}{5, 5}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+}
+
+func TestNamedWithMapArgMissingParam(t *testing.T) {
+	withBind(t, BindQuestion)
+
+	if _, _, err := Named("SELECT * FROM t WHERE id = :id", map[string]interface{}{}); err == nil {
+		t.Fatalf("expected error for missing named parameter")
+	}
+}
+
+func TestNamedWithStructArg(t *testing.T) {
+	withBind(t, BindDollar)
+
+	type filter struct {
+		ID   int64 `db:"id"`
+		Name string
+	}
+
+	query, args, err := Named("SELECT * FROM t WHERE id = :id AND name = :name", filter{ID: 7, Name: "Ann"})
+	if err != nil {
+		t.Fatalf("Named: %v", err)
+	}
+
+	if want := "SELECT * FROM t WHERE id = $1 AND name = $2"; query != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+	if want := []interface{}{int64(7), "Ann"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+}
@@ -0,0 +1,69 @@
+package sqlhelper
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// StmtScanAllSets performs the prepared statement stmt with arguments args and scans each result set it
+// produces into the corresponding element of dsts, advancing between sets with rows.NextResultSet(). This
+// is how a stored procedure or a batched query returning several differently-shaped result sets is
+// consumed. It returns an error if the number of result sets does not match len(dsts).
+func StmtScanAllSets(stmt *sql.Stmt, dsts []MultiScannable, args ...interface{}) error {
+	return StmtScanAllSetsContext(context.Background(), stmt, dsts, args...)
+}
+
+// StmtScanAllSetsContext is the context-aware variant of StmtScanAllSets.
+func StmtScanAllSetsContext(ctx context.Context, stmt *sql.Stmt, dsts []MultiScannable, args ...interface{}) error {
+	return QueryScanAllSets(ctx, stmtQueryer{stmt}, dsts, "", args...)
+}
+
+// QueryScanAllSets runs query with arguments args against q and scans each result set it produces into
+// the corresponding element of dsts, as described in StmtScanAllSets.
+func QueryScanAllSets(ctx context.Context, q Queryer, dsts []MultiScannable, query string, args ...interface{}) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := scanAllResultSets(rows, dsts); err != nil {
+		return err
+	}
+
+	return rows.Err()
+}
+
+// TxQueryScanAllSets is QueryScanAllSets specialized for *sql.Tx.
+func TxQueryScanAllSets(ctx context.Context, tx *sql.Tx, dsts []MultiScannable, query string, args ...interface{}) error {
+	return QueryScanAllSets(ctx, tx, dsts, query, args...)
+}
+
+// scanAllResultSets scans rows' current result set into dsts[0], then advances with rows.NextResultSet()
+// for each subsequent element of dsts. Each result set may have a different column shape, so no state is
+// carried over between them. It leaves closing rows and checking rows.Err() to the caller.
+func scanAllResultSets(rows *sql.Rows, dsts []MultiScannable) error {
+	if len(dsts) == 0 {
+		return fmt.Errorf("sqlhelper: expected 0 result sets, got at least 1")
+	}
+
+	for i, dst := range dsts {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("sqlhelper: expected %d result sets, got %d", len(dsts), i)
+		}
+
+		if err := scanAllRows(rows, dst); err != nil {
+			return err
+		}
+	}
+
+	if rows.NextResultSet() {
+		return fmt.Errorf("sqlhelper: expected %d result sets, got more", len(dsts))
+	}
+
+	return nil
+}
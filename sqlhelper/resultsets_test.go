@@ -0,0 +1,125 @@
+package sqlhelper
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestStmtScanAllSetsSuccess(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+		},
+		moreSets: []fakeResultSet{
+			{
+				columns: []string{"id", "name"},
+				rows: [][]driver.Value{
+					{int64(2), "Bob"},
+					{int64(3), "Cid"},
+				},
+			},
+		},
+	})
+
+	stmt, err := db.Prepare("call get_labels()")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	var first, second ctxLabels
+	err = StmtScanAllSets(stmt, []MultiScannable{&first, &second})
+	if err != nil {
+		t.Fatalf("StmtScanAllSets: %v", err)
+	}
+
+	if want := (ctxLabels{{ID: 1, Name: "Ann"}}); !reflect.DeepEqual(first, want) {
+		t.Fatalf("first = %+v, want %+v", first, want)
+	}
+	if want := (ctxLabels{{ID: 2, Name: "Bob"}, {ID: 3, Name: "Cid"}}); !reflect.DeepEqual(second, want) {
+		t.Fatalf("second = %+v, want %+v", second, want)
+	}
+}
+
+func TestStmtScanAllSetsTooFewSets(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+		},
+	})
+
+	stmt, err := db.Prepare("call get_labels()")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	var first, second ctxLabels
+	err = StmtScanAllSets(stmt, []MultiScannable{&first, &second})
+	if err == nil {
+		t.Fatalf("expected an error when fewer result sets are produced than requested")
+	}
+}
+
+func TestStmtScanAllSetsTooManySets(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+		},
+		moreSets: []fakeResultSet{
+			{
+				columns: []string{"id", "name"},
+				rows: [][]driver.Value{
+					{int64(2), "Bob"},
+				},
+			},
+		},
+	})
+
+	stmt, err := db.Prepare("call get_labels()")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	var first ctxLabels
+	err = StmtScanAllSets(stmt, []MultiScannable{&first})
+	if err == nil {
+		t.Fatalf("expected an error when more result sets are produced than requested")
+	}
+}
+
+func TestQueryScanAllSetsSuccess(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+		},
+		moreSets: []fakeResultSet{
+			{
+				columns: []string{"id", "name"},
+				rows: [][]driver.Value{
+					{int64(2), "Bob"},
+				},
+			},
+		},
+	})
+
+	var first, second ctxLabels
+	err := QueryScanAllSets(context.Background(), db, []MultiScannable{&first, &second}, "call get_labels()")
+	if err != nil {
+		t.Fatalf("QueryScanAllSets: %v", err)
+	}
+
+	if want := (ctxLabels{{ID: 1, Name: "Ann"}}); !reflect.DeepEqual(first, want) {
+		t.Fatalf("first = %+v, want %+v", first, want)
+	}
+	if want := (ctxLabels{{ID: 2, Name: "Bob"}}); !reflect.DeepEqual(second, want) {
+		t.Fatalf("second = %+v, want %+v", second, want)
+	}
+}
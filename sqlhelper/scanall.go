@@ -1,7 +1,14 @@
 // Package sqlhelper provides simple interface to perform prepared statement and store all result (including multiple rows) at once.
 package sqlhelper
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrTooManyRows is returned by the ScanOne family of functions when the result set has more than one row.
+var ErrTooManyRows = errors.New("sqlhelper: more than one row in result set")
 
 // SingleScannable represent object in what single row can be saved.
 type SingleScannable interface {
@@ -44,12 +51,12 @@ type MultiScannable interface {
 //  	return err
 //  }
 func StmtScanAll(stmt *sql.Stmt, dst MultiScannable, args ...interface{}) error {
-	rows, err := stmt.Query(args...)
-	if err != nil {
-		return err
-	}
-	defer rows.Close()
+	return StmtScanAllContext(context.Background(), stmt, dst, args...)
+}
 
+// scanAllRows scans every remaining row of rows into dst. It leaves closing rows and checking rows.Err()
+// to the caller.
+func scanAllRows(rows *sql.Rows, dst MultiScannable) error {
 	for rows.Next() {
 		rowContainer := dst.NewElement()
 		if err := rows.Scan(rowContainer.SqlScanInterface()...); err != nil {
@@ -57,5 +64,26 @@ func StmtScanAll(stmt *sql.Stmt, dst MultiScannable, args ...interface{}) error
 		}
 	}
 
+	return nil
+}
+
+// scanOneRow scans a single row of rows into dst. It returns sql.ErrNoRows
+// if rows has no row, and an error if it has more than one.
+func scanOneRow(rows *sql.Rows, dst SingleScannable) error {
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := rows.Scan(dst.SqlScanInterface()...); err != nil {
+		return err
+	}
+
+	if rows.Next() {
+		return ErrTooManyRows
+	}
+
 	return rows.Err()
 }
\ No newline at end of file
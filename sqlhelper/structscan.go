@@ -0,0 +1,249 @@
+package sqlhelper
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TagName is the struct tag key used to map result columns to struct fields for StmtStructScanAll and
+// StmtStructScanOne. A field tagged `db:"-"` is always skipped.
+var TagName = "db"
+
+// NameMapper is used to derive a column name from a struct field name when the field has no TagName tag.
+// It defaults to strings.ToLower, mirroring the common convention of lower_case column names.
+var NameMapper = strings.ToLower
+
+// structFields maps a column name to the index path (for reflect.Value.FieldByIndex) of the struct field
+// holding it. Embedded struct fields are flattened into their parent, so the index path may have more
+// than one element.
+type structFields map[string][]int
+
+var (
+	structFieldsCacheMu sync.RWMutex
+	structFieldsCache   = map[reflect.Type]structFields{}
+)
+
+// fieldsOf returns the structFields for t, building and caching it on first use.
+func fieldsOf(t reflect.Type) structFields {
+	structFieldsCacheMu.RLock()
+	fields, ok := structFieldsCache[t]
+	structFieldsCacheMu.RUnlock()
+	if ok {
+		return fields
+	}
+
+	fields = structFields{}
+	collectFields(t, nil, fields)
+
+	structFieldsCacheMu.Lock()
+	structFieldsCache[t] = fields
+	structFieldsCacheMu.Unlock()
+
+	return fields
+}
+
+// collectFields walks t's fields, recording each into fields under prefix+index. Anonymous struct fields
+// without an explicit tag are traversed recursively instead of being recorded themselves.
+func collectFields(t reflect.Type, prefix []int, fields structFields) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue // unexported
+		}
+
+		tag := f.Tag.Get(TagName)
+		if tag == "-" {
+			continue
+		}
+
+		index := make([]int, len(prefix)+1)
+		copy(index, prefix)
+		index[len(prefix)] = i
+
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectFields(ft, index, fields)
+				continue
+			}
+		}
+
+		name := tag
+		if name == "" {
+			name = NameMapper(f.Name)
+		}
+		fields[name] = index
+	}
+}
+
+// fieldByIndex returns v's field at index, allocating any nil anonymous pointer-to-struct field it walks
+// through along the way so the result can be addressed for scanning. v must be addressable.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// columnIndexes resolves each of columns to a field index path within fields, returning an error naming
+// the first column that has no matching field.
+func columnIndexes(columns []string, fields structFields) ([][]int, error) {
+	indexes := make([][]int, len(columns))
+	for i, c := range columns {
+		index, ok := fields[c]
+		if !ok {
+			return nil, fmt.Errorf("sqlhelper: no destination field for column %q", c)
+		}
+		indexes[i] = index
+	}
+	return indexes, nil
+}
+
+// structScanRows scans every remaining row of rows into new elements appended to dst, which must be a
+// pointer to a slice of struct or *struct. It is the reflection-based counterpart of MultiScannable, used
+// by StmtStructScanAll when dst does not implement it.
+func structScanRows(rows *sql.Rows, dst interface{}) error {
+	dstVal := reflect.ValueOf(dst)
+	if dstVal.Kind() != reflect.Ptr || dstVal.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("sqlhelper: dst must be a pointer to a slice, got %T", dst)
+	}
+	sliceVal := dstVal.Elem()
+
+	elemType := sliceVal.Type().Elem()
+	structType := elemType
+	elemIsPtr := structType.Kind() == reflect.Ptr
+	if elemIsPtr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("sqlhelper: slice element must be a struct or a pointer to a struct, got %v", elemType)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	indexes, err := columnIndexes(columns, fieldsOf(structType))
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		elem := reflect.New(structType)
+		dests := make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			dests[i] = fieldByIndex(elem.Elem(), index).Addr().Interface()
+		}
+		if err := rows.Scan(dests...); err != nil {
+			return err
+		}
+
+		if elemIsPtr {
+			sliceVal.Set(reflect.Append(sliceVal, elem))
+		} else {
+			sliceVal.Set(reflect.Append(sliceVal, elem.Elem()))
+		}
+	}
+
+	return nil
+}
+
+// structScanOneRow scans a single row of rows into dst, which must be a pointer to a struct. It returns
+// sql.ErrNoRows if rows has no row, and an error if it has more than one.
+func structScanOneRow(rows *sql.Rows, dst interface{}) error {
+	dstVal := reflect.ValueOf(dst)
+	if dstVal.Kind() != reflect.Ptr || dstVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("sqlhelper: dst must be a pointer to a struct, got %T", dst)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	indexes, err := columnIndexes(columns, fieldsOf(dstVal.Elem().Type()))
+	if err != nil {
+		return err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	dests := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		dests[i] = fieldByIndex(dstVal.Elem(), index).Addr().Interface()
+	}
+	if err := rows.Scan(dests...); err != nil {
+		return err
+	}
+
+	if rows.Next() {
+		return ErrTooManyRows
+	}
+
+	return rows.Err()
+}
+
+// StmtStructScanAll performs the prepared statement stmt with arguments args and stores all result rows
+// in dst, a pointer to a slice of struct or *struct. Columns are mapped to fields by TagName tag, falling
+// back to NameMapper(field name) when a field has no tag. Embedded structs are traversed recursively, and
+// a field tagged `db:"-"` is skipped. Unlike StmtScanAll, the element type needs no SqlScanInterface
+// method. If dst implements MultiScannable, the rows are scanned through it as StmtScanAll does instead.
+// Example:
+//  type Label struct {
+//  	Id   int32  `db:"id"`
+//  	Name string `db:"name"`
+//  }
+//
+//  var labels []*Label
+//  if err := sqlhelper.StmtStructScanAll(someStmtGetLabels, &labels, someId); err != nil {
+//  	return err
+//  }
+func StmtStructScanAll(stmt *sql.Stmt, dst interface{}, args ...interface{}) error {
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if ms, ok := dst.(MultiScannable); ok {
+		err = scanAllRows(rows, ms)
+	} else {
+		err = structScanRows(rows, dst)
+	}
+	if err != nil {
+		return err
+	}
+
+	return rows.Err()
+}
+
+// StmtStructScanOne performs the prepared statement stmt with arguments args and stores the single result
+// row in dst, a pointer to a struct, mapping columns to fields as described in StmtStructScanAll. It
+// returns sql.ErrNoRows if the result set is empty, and an error if it has more than one row.
+func StmtStructScanOne(stmt *sql.Stmt, dst interface{}, args ...interface{}) error {
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return structScanOneRow(rows, dst)
+}
@@ -0,0 +1,275 @@
+package sqlhelper
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type structScanInner struct {
+	City string `db:"city"`
+}
+
+type structScanOuter struct {
+	ID      int32 `db:"id"`
+	Name    string
+	Skipped string `db:"-"`
+	structScanInner
+}
+
+type StructScanPtrInner struct {
+	Country string `db:"country"`
+}
+
+type structScanPtrOuter struct {
+	ID int32 `db:"id"`
+	*StructScanPtrInner
+}
+
+func TestFieldsOfTaggedUntaggedAndSkipped(t *testing.T) {
+	fields := fieldsOf(reflect.TypeOf(structScanOuter{}))
+
+	if index, ok := fields["id"]; !ok || !reflect.DeepEqual(index, []int{0}) {
+		t.Fatalf(`fields["id"] = %v, %v; want [0], true`, index, ok)
+	}
+	if index, ok := fields["name"]; !ok || !reflect.DeepEqual(index, []int{1}) {
+		t.Fatalf(`fields["name"] = %v, %v; want [1], true (NameMapper fallback)`, index, ok)
+	}
+	if _, ok := fields["skipped"]; ok {
+		t.Fatalf(`fields["skipped"] should not be mapped, field is tagged db:"-"`)
+	}
+}
+
+func TestFieldsOfEmbeddedStruct(t *testing.T) {
+	fields := fieldsOf(reflect.TypeOf(structScanOuter{}))
+
+	index, ok := fields["city"]
+	if !ok {
+		t.Fatalf(`fields["city"] missing, embedded struct field was not flattened`)
+	}
+	if len(index) != 2 {
+		t.Fatalf(`fields["city"] = %v, want a 2-element index path into the embedded struct`, index)
+	}
+}
+
+// fakeDriver is a minimal database/sql/driver.Driver used to exercise StmtStructScanAll end-to-end
+// without depending on a real database.
+type fakeDriver struct{}
+
+type fakeDataset struct {
+	columns []string
+	rows    [][]driver.Value
+	// moreSets holds any result sets beyond the first, for tests exercising rows.NextResultSet().
+	moreSets []fakeResultSet
+}
+
+type fakeResultSet struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+var (
+	fakeDatasetsMu sync.Mutex
+	fakeDatasets   = map[string]*fakeDataset{}
+)
+
+func registerFakeDataset(t *testing.T, ds *fakeDataset) string {
+	t.Helper()
+
+	name := t.Name()
+
+	fakeDatasetsMu.Lock()
+	fakeDatasets[name] = ds
+	fakeDatasetsMu.Unlock()
+
+	return name
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDatasetsMu.Lock()
+	ds, ok := fakeDatasets[name]
+	fakeDatasetsMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("sqlhelper: no fake dataset registered for %q", name)
+	}
+	return &fakeConn{dataset: ds}, nil
+}
+
+type fakeConn struct {
+	dataset *fakeDataset
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{dataset: c.dataset}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+// fakeTx is a no-op driver.Tx: the fake driver has no state to commit or roll back.
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	dataset *fakeDataset
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("sqlhelper: fake driver does not support Exec")
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	sets := make([]fakeResultSet, 0, 1+len(s.dataset.moreSets))
+	sets = append(sets, fakeResultSet{columns: s.dataset.columns, rows: s.dataset.rows})
+	sets = append(sets, s.dataset.moreSets...)
+	return &fakeRows{sets: sets}, nil
+}
+
+// fakeRows implements driver.Rows and driver.RowsNextResultSet over a fixed list of result sets.
+type fakeRows struct {
+	sets   []fakeResultSet
+	setIdx int
+	pos    int
+}
+
+func (r *fakeRows) Columns() []string { return r.sets[r.setIdx].columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	rows := r.sets[r.setIdx].rows
+	if r.pos >= len(rows) {
+		return io.EOF
+	}
+	copy(dest, rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func (r *fakeRows) HasNextResultSet() bool { return r.setIdx+1 < len(r.sets) }
+
+func (r *fakeRows) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+	r.setIdx++
+	r.pos = 0
+	return nil
+}
+
+func init() {
+	sql.Register("sqlhelper_fake", fakeDriver{})
+}
+
+func openFakeDB(t *testing.T, ds *fakeDataset) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlhelper_fake", registerFakeDataset(t, ds))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+type scanPerson struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestStmtStructScanAllValueSlice(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+			{int64(2), "Bob"},
+		},
+	})
+
+	stmt, err := db.Prepare("select id, name from people")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	var people []scanPerson
+	if err := StmtStructScanAll(stmt, &people); err != nil {
+		t.Fatalf("StmtStructScanAll: %v", err)
+	}
+
+	want := []scanPerson{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Bob"}}
+	if !reflect.DeepEqual(people, want) {
+		t.Fatalf("people = %+v, want %+v", people, want)
+	}
+}
+
+func TestStmtStructScanAllPointerSlice(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "name"},
+		rows: [][]driver.Value{
+			{int64(1), "Ann"},
+		},
+	})
+
+	stmt, err := db.Prepare("select id, name from people")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	var people []*scanPerson
+	if err := StmtStructScanAll(stmt, &people); err != nil {
+		t.Fatalf("StmtStructScanAll: %v", err)
+	}
+
+	if len(people) != 1 || people[0].ID != 1 || people[0].Name != "Ann" {
+		t.Fatalf("people = %+v, want a single {1 Ann}", people)
+	}
+}
+
+func TestStmtStructScanAllAnonymousPointerField(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "country"},
+		rows: [][]driver.Value{
+			{int64(1), "France"},
+		},
+	})
+
+	stmt, err := db.Prepare("select id, country from places")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	var places []structScanPtrOuter
+	if err := StmtStructScanAll(stmt, &places); err != nil {
+		t.Fatalf("StmtStructScanAll: %v", err)
+	}
+
+	if len(places) != 1 || places[0].ID != 1 || places[0].StructScanPtrInner == nil || places[0].Country != "France" {
+		t.Fatalf("places = %+v, want a single {1 &{France}}", places)
+	}
+}
+
+func TestStmtStructScanAllUnknownColumn(t *testing.T) {
+	db := openFakeDB(t, &fakeDataset{
+		columns: []string{"id", "unknown"},
+		rows: [][]driver.Value{
+			{int64(1), "x"},
+		},
+	})
+
+	stmt, err := db.Prepare("select id, unknown from people")
+	if err != nil {
+		t.Fatalf("db.Prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	var people []scanPerson
+	if err := StmtStructScanAll(stmt, &people); err == nil {
+		t.Fatalf("expected an error for a column with no destination field")
+	}
+}